@@ -1,20 +1,31 @@
 package cli
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"hash"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/peterbourgon/ff/v3/ffcli"
 	"github.com/pkg/errors"
 
+	"github.com/philips-labs/slsa-provenance-action/lib/dsse"
 	"github.com/philips-labs/slsa-provenance-action/lib/github"
 	"github.com/philips-labs/slsa-provenance-action/lib/intoto"
 )
@@ -24,6 +35,9 @@ const (
 	selfHostedIDSuffix   = "/Attestations/SelfHostedActions@v1"
 	typeID               = "https://github.com/Attestations/GitHubActionsWorkflow@v1"
 	payloadContentType   = "application/vnd.in-toto+json"
+
+	predicateVersionV02 = "v0.2"
+	predicateVersionV1  = "v1.0"
 )
 
 // RequiredFlagError creates an error flag error for the given flag name
@@ -31,33 +45,214 @@ func RequiredFlagError(flagName string) error {
 	return fmt.Errorf("no value found for required flag: %s", flagName)
 }
 
-// subjects walks the file or directory at "root" and hashes all files.
-func subjects(root string) ([]intoto.Subject, error) {
-	var s []intoto.Subject
-	return s, filepath.Walk(root, func(abspath string, info fs.FileInfo, err error) error {
-		if err != nil {
-			return err
+// digestAlgorithm pairs a DigestSet key with a constructor for its hash.Hash.
+type digestAlgorithm struct {
+	name string
+	new  func() hash.Hash
+}
+
+var supportedDigestAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+	"sha1":   sha1.New,
+}
+
+// parseDigestAlgorithms parses a comma-separated list of digest algorithm
+// names (e.g. "sha256,sha512") into their hash.Hash constructors.
+func parseDigestAlgorithms(csv string) ([]digestAlgorithm, error) {
+	var algos []digestAlgorithm
+	for _, name := range strings.Split(csv, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
 		}
-		if info.IsDir() {
+		newHash, ok := supportedDigestAlgorithms[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported -digest-algorithms entry: %s", name)
+		}
+		algos = append(algos, digestAlgorithm{name: name, new: newHash})
+	}
+	if len(algos) == 0 {
+		return nil, fmt.Errorf("-digest-algorithms must name at least one algorithm")
+	}
+	return algos, nil
+}
+
+// hashFile streams the file at abspath through one hash.Hash per algo in a
+// single pass, returning a Subject named relpath with the resulting DigestSet.
+func hashFile(abspath, relpath string, algos []digestAlgorithm) (intoto.Subject, error) {
+	f, err := os.Open(abspath)
+	if err != nil {
+		return intoto.Subject{}, err
+	}
+	defer f.Close()
+
+	hashers := make([]hash.Hash, len(algos))
+	writers := make([]io.Writer, len(algos))
+	for i, algo := range algos {
+		hashers[i] = algo.new()
+		writers[i] = hashers[i]
+	}
+
+	if _, err := io.Copy(io.MultiWriter(writers...), f); err != nil {
+		return intoto.Subject{}, err
+	}
+
+	digest := make(intoto.DigestSet, len(algos))
+	for i, algo := range algos {
+		digest[algo.name] = hex.EncodeToString(hashers[i].Sum(nil))
+	}
+
+	return intoto.Subject{Name: relpath, Digest: digest}, nil
+}
+
+// subjectsFromPath walks the file or directory at "root" and hashes every
+// file with the given digest algorithms, using a pool of workers consuming
+// paths off of filepath.WalkDir. The result is sorted by relative path so
+// that output stays deterministic despite the workers completing out of order.
+func subjectsFromPath(root string, workers int, algos []digestAlgorithm) ([]intoto.Subject, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	type job struct {
+		abspath string
+		relpath string
+	}
+	type result struct {
+		subject intoto.Subject
+		err     error
+	}
+
+	jobs := make(chan job)
+	results := make(chan result)
+
+	var workerGroup sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		workerGroup.Add(1)
+		go func() {
+			defer workerGroup.Done()
+			for j := range jobs {
+				subject, err := hashFile(j.abspath, j.relpath, algos)
+				results <- result{subject: subject, err: err}
+			}
+		}()
+	}
+	go func() {
+		workerGroup.Wait()
+		close(results)
+	}()
+
+	walkErrCh := make(chan error, 1)
+	go func() {
+		defer close(jobs)
+		walkErrCh <- filepath.WalkDir(root, func(abspath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			relpath, err := filepath.Rel(root, abspath)
+			if err != nil {
+				return err
+			}
+			// Note: filepath.Rel() returns "." when "root" and "abspath" point to the same file.
+			if relpath == "." {
+				relpath = filepath.Base(root)
+			}
+			jobs <- job{abspath: abspath, relpath: relpath}
 			return nil
+		})
+	}()
+
+	var s []intoto.Subject
+	var firstErr error
+	for r := range results {
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		s = append(s, r.subject)
+	}
+	if err := <-walkErrCh; err != nil && firstErr == nil {
+		firstErr = err
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(s, func(i, j int) bool { return s[i].Name < s[j].Name })
+	return s, nil
+}
+
+// subjectsFromSHA256Sum parses the base64-encoded output of `sha256sum`
+// (lines of "<64 hex char sha256>  <name>") into a list of Subjects. It
+// rejects malformed digests and duplicate names.
+func subjectsFromSHA256Sum(base64Subjects string) ([]intoto.Subject, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(base64Subjects))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to base64-decode subjects")
+	}
+
+	var s []intoto.Subject
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(decoded))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
 		}
-		relpath, err := filepath.Rel(root, abspath)
-		if err != nil {
-			return err
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("malformed subjects line: %q", line)
 		}
-		// Note: filepath.Rel() returns "." when "root" and "abspath" point to the same file.
-		if relpath == "." {
-			relpath = filepath.Base(root)
+		sha := fields[0]
+		name := strings.TrimPrefix(strings.Join(fields[1:], " "), "*")
+
+		if len(sha) != 64 || !isHexString(sha) {
+			return nil, fmt.Errorf("invalid sha256 digest %q for subject %q", sha, name)
 		}
-		contents, err := os.ReadFile(abspath)
-		if err != nil {
-			return err
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate subject name: %q", name)
 		}
-		sha := sha256.Sum256(contents)
-		shaHex := hex.EncodeToString(sha[:])
-		s = append(s, intoto.Subject{Name: relpath, Digest: intoto.DigestSet{"sha256": shaHex}})
-		return nil
-	})
+		seen[name] = true
+
+		s = append(s, intoto.Subject{Name: name, Digest: intoto.DigestSet{"sha256": sha}})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to scan subjects")
+	}
+
+	return s, nil
+}
+
+// predicateBuilderFor resolves a built-in intoto.PredicateBuilder from the
+// --predicate-type name, reading its content from raw.
+func predicateBuilderFor(predicateType string, raw json.RawMessage) (intoto.PredicateBuilder, error) {
+	switch predicateType {
+	case intoto.PredicateTypeSPDX:
+		return intoto.NewSPDXBuilder(raw), nil
+	case intoto.PredicateTypeCycloneDX:
+		return intoto.NewCycloneDXBuilder(raw), nil
+	case intoto.PredicateTypeLink:
+		return intoto.NewLinkBuilder(raw), nil
+	default:
+		return nil, fmt.Errorf("unsupported -predicate-type: %s", predicateType)
+	}
+}
+
+func isHexString(s string) bool {
+	for _, r := range s {
+		if !((r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')) {
+			return false
+		}
+	}
+	return true
 }
 
 func builderID(repoURI string) string {
@@ -67,14 +262,85 @@ func builderID(repoURI string) string {
 	return repoURI + selfHostedIDSuffix
 }
 
+// slsaProvenanceV1Statement builds a SLSA v1.0 predicate-shaped Statement
+// from the same GitHub context used for the v0.2 statement. When invocation
+// carries a ConfigSource (i.e. it was derived from an OIDC token), it takes
+// precedence over the repoURI/gh.SHA/gh.Workflow heuristics for the
+// resolved dependency and workflow entry point.
+func slsaProvenanceV1Statement(subjects []intoto.Subject, builderIDValue, repoURI string, gh github.Context, event github.AnyEvent, invocation intoto.Invocation) intoto.StatementV1 {
+	dependency := intoto.Item{URI: "git+" + repoURI, Digest: intoto.DigestSet{"sha1": gh.SHA}}
+	workflow := gh.Workflow
+	if invocation.ConfigSource.URI != "" {
+		dependency = intoto.Item{URI: invocation.ConfigSource.URI, Digest: invocation.ConfigSource.Digest}
+		workflow = invocation.ConfigSource.EntryPoint
+	}
+
+	return intoto.SLSAProvenanceV1Statement(
+		intoto.WithSubjectV1(subjects),
+		intoto.WithBuildDefinition(intoto.BuildDefinition{
+			BuildType: typeID,
+			ExternalParameters: intoto.ExternalParameters{
+				Workflow: workflow,
+				Inputs:   event.Inputs,
+			},
+			ResolvedDependencies: []intoto.Item{dependency},
+		}),
+		intoto.WithRunDetails(intoto.RunDetails{
+			Builder:  intoto.Builder{ID: builderIDValue},
+			Metadata: intoto.RunMetadata{InvocationID: repoURI + "/actions/runs/" + gh.RunID},
+		}),
+	)
+}
+
+// builderIdentity resolves the builder ID to embed in generated provenance,
+// and an optional Invocation describing the workflow's configuration
+// source. When useOIDC is set, both are derived from the ambient GitHub
+// Actions OIDC token's claims rather than the GITHUB_ACTIONS env heuristic;
+// the caller is responsible for persisting rawToken.
+func builderIdentity(useOIDC bool, repoURI string) (builderIDValue string, invocation intoto.Invocation, rawToken string, err error) {
+	if !useOIDC {
+		return builderID(repoURI), intoto.Invocation{}, "", nil
+	}
+
+	rawToken, claims, err := github.RequestOIDCToken("sigstore")
+	if err != nil {
+		return "", intoto.Invocation{}, "", errors.Wrap(err, "failed to obtain OIDC token")
+	}
+
+	repo, entryPoint, ref, err := github.SplitJobWorkflowRef(claims.JobWorkflowRef)
+	if err != nil {
+		return "", intoto.Invocation{}, "", errors.Wrap(err, "failed to parse OIDC token claims")
+	}
+
+	builderIDValue = "https://github.com/" + claims.JobWorkflowRef
+	invocation = intoto.Invocation{
+		ConfigSource: intoto.ConfigSource{
+			URI:        "git+https://github.com/" + repo + "@" + ref,
+			Digest:     intoto.DigestSet{"sha1": claims.JobWorkflowSha},
+			EntryPoint: entryPoint,
+		},
+	}
+
+	return builderIDValue, invocation, rawToken, nil
+}
+
 // Generate creates an instance of *ffcli.Command to generate provenance
 func Generate(w io.Writer) *ffcli.Command {
 	var (
-		flagset       = flag.NewFlagSet("slsa-provenance generate", flag.ExitOnError)
-		artifactPath  = flagset.String("artifact_path", "", "The file or dir path of the artifacts for which provenance should be generated.")
-		outputPath    = flagset.String("output_path", "build.provenance", "The path to which the generated provenance should be written.")
-		githubContext = flagset.String("github_context", "", "The '${github}' context value.")
-		runnerContext = flagset.String("runner_context", "", "The '${runner}' context value.")
+		flagset          = flag.NewFlagSet("slsa-provenance generate", flag.ExitOnError)
+		artifactPath     = flagset.String("artifact_path", "", "The file or dir path of the artifacts for which provenance should be generated.")
+		subjectsFile     = flagset.String("subjects-file", "", "A path to a file, or an inline base64 string, of base64-encoded `sha256sum` output to use as subjects. Mutually exclusive with -artifact_path.")
+		outputPath       = flagset.String("output_path", "build.provenance", "The path to which the generated provenance should be written.")
+		githubContext    = flagset.String("github_context", "", "The '${github}' context value.")
+		runnerContext    = flagset.String("runner_context", "", "The '${runner}' context value.")
+		predicateVersion = flagset.String("predicate-version", predicateVersionV02, "The SLSA provenance predicate shape to emit, 'v0.2' or 'v1.0'.")
+		sign             = flagset.Bool("sign", false, "Also wrap the generated statement in a signed DSSE envelope, written to -output_path with a '.intoto.jsonl' suffix.")
+		signingKey       = flagset.String("key", "", "Path to a PEM-encoded ECDSA private key to sign with. Required when -sign is set.")
+		predicateType    = flagset.String("predicate-type", intoto.PredicateTypeSLSAProvenance, "The predicate type to emit: 'slsaprovenance', 'spdx', 'cyclonedx' or 'link'.")
+		predicateFile    = flagset.String("predicate-file", "", "Path to a JSON predicate document. Required unless -predicate-type is 'slsaprovenance'.")
+		hashWorkers      = flagset.Int("hash-workers", runtime.NumCPU(), "The number of concurrent workers used to hash artifacts under -artifact_path.")
+		digestAlgorithms = flagset.String("digest-algorithms", "sha256", "A comma-separated list of digest algorithms to compute for each artifact, e.g. 'sha256,sha512,sha1'.")
+		useOIDC          = flagset.Bool("use-oidc", false, "Derive the builder ID and invocation config source from the ambient GitHub Actions OIDC token instead of the GITHUB_ACTIONS env heuristic. Requires 'permissions: id-token: write'.")
 	)
 
 	flagset.SetOutput(w)
@@ -85,10 +351,14 @@ func Generate(w io.Writer) *ffcli.Command {
 		ShortHelp:  "Generates the slsa provenance file",
 		FlagSet:    flagset,
 		Exec: func(ctx context.Context, args []string) error {
-			if *artifactPath == "" {
+			if *artifactPath == "" && *subjectsFile == "" {
 				flagset.Usage()
 				return RequiredFlagError("-artifact_path")
 			}
+			if *artifactPath != "" && *subjectsFile != "" {
+				flagset.Usage()
+				return fmt.Errorf("-artifact_path and -subjects-file are mutually exclusive")
+			}
 			if *outputPath == "" {
 				flagset.Usage()
 				return RequiredFlagError("-output_path")
@@ -101,14 +371,98 @@ func Generate(w io.Writer) *ffcli.Command {
 				flagset.Usage()
 				return RequiredFlagError("-runner_context")
 			}
-
-			subjects, err := subjects(*artifactPath)
-			if os.IsNotExist(err) {
-				return fmt.Errorf("resource path not found: [provided=%s]", *artifactPath)
-			} else if err != nil {
+			if *predicateVersion != predicateVersionV02 && *predicateVersion != predicateVersionV1 {
+				flagset.Usage()
+				return fmt.Errorf("unsupported -predicate-version: %s (want %q or %q)", *predicateVersion, predicateVersionV02, predicateVersionV1)
+			}
+			if *sign && *signingKey == "" {
+				flagset.Usage()
+				return RequiredFlagError("-key")
+			}
+			if *predicateType != intoto.PredicateTypeSLSAProvenance && *predicateFile == "" {
+				flagset.Usage()
+				return RequiredFlagError("-predicate-file")
+			}
+			if *predicateType != intoto.PredicateTypeSLSAProvenance && *useOIDC {
+				flagset.Usage()
+				return fmt.Errorf("-use-oidc only applies to -predicate-type=%s: it derives SLSA builder/invocation metadata that %s predicates don't have", intoto.PredicateTypeSLSAProvenance, *predicateType)
+			}
+			algos, err := parseDigestAlgorithms(*digestAlgorithms)
+			if err != nil {
+				flagset.Usage()
 				return err
 			}
 
+			var subjects []intoto.Subject
+			if *subjectsFile != "" {
+				base64Subjects := *subjectsFile
+				contents, err := os.ReadFile(*subjectsFile)
+				if err == nil {
+					base64Subjects = string(contents)
+				} else if !os.IsNotExist(err) {
+					return errors.Wrap(err, "failed to read -subjects-file")
+				}
+
+				subjects, err = subjectsFromSHA256Sum(base64Subjects)
+				if err != nil {
+					return errors.Wrap(err, "failed to parse -subjects-file")
+				}
+			} else {
+				subjects, err = subjectsFromPath(*artifactPath, *hashWorkers, algos)
+				if os.IsNotExist(err) {
+					return fmt.Errorf("resource path not found: [provided=%s]", *artifactPath)
+				} else if err != nil {
+					return err
+				}
+			}
+
+			if *predicateType != intoto.PredicateTypeSLSAProvenance {
+				raw, err := os.ReadFile(*predicateFile)
+				if err != nil {
+					return errors.Wrap(err, "failed to read -predicate-file")
+				}
+
+				builder, err := predicateBuilderFor(*predicateType, raw)
+				if err != nil {
+					return err
+				}
+
+				stmt, err := intoto.NewGenericStatement(subjects, builder)
+				if err != nil {
+					return errors.Wrap(err, "failed to build statement")
+				}
+
+				payload, err := json.MarshalIndent(stmt, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "failed to marshal statement")
+				}
+
+				fmt.Fprintf(w, "Saving provenance to %s:\n\n%s\n", *outputPath, string(payload))
+				if err := os.WriteFile(*outputPath, payload, 0755); err != nil {
+					return errors.Wrap(err, "failed to write provenance")
+				}
+
+				if *sign {
+					signer, err := dsse.NewECDSASignerFromFile(*signingKey)
+					if err != nil {
+						return errors.Wrap(err, "failed to load signing key")
+					}
+
+					line, _, err := signEnvelope(payload, signer, nil)
+					if err != nil {
+						return err
+					}
+
+					envelopePath := *outputPath + ".intoto.jsonl"
+					fmt.Fprintf(w, "Saving signed provenance to %s\n", envelopePath)
+					if err := os.WriteFile(envelopePath, line, 0755); err != nil {
+						return errors.Wrap(err, "failed to write signed envelope")
+					}
+				}
+
+				return nil
+			}
+
 			anyCtx := github.AnyContext{}
 			if err := json.Unmarshal([]byte(*githubContext), &anyCtx.Context); err != nil {
 				return errors.Wrap(err, "failed to unmarshal github context json")
@@ -121,39 +475,78 @@ func Generate(w io.Writer) *ffcli.Command {
 			// NOTE: Re-runs are not uniquely identified and can cause run ID collisions.
 			repoURI := "https://github.com/" + gh.Repository
 
-			stmt := intoto.SLSAProvenanceStatement(
-				intoto.WithSubject(subjects),
-				intoto.WithBuilder(builderID(repoURI)),
-				intoto.WithMetadata(repoURI+"/actions/runs/"+gh.RunID),
-			)
-
-			stmt.Predicate.Recipe = intoto.Recipe{
-				Type:              typeID,
-				DefinedInMaterial: 0,
-			}
-			stmt.Predicate.Materials = []intoto.Item{}
-
-			// NOTE: This is inexact as multiple workflows in a repo can have the same name.
-			// See https://github.com/github/feedback/discussions/4188
-			stmt.Predicate.Recipe.EntryPoint = gh.Workflow
 			event := github.AnyEvent{}
 			if err := json.Unmarshal(gh.Event, &event); err != nil {
 				return errors.Wrap(err, "failed to unmarshal github context event json")
 			}
 
-			stmt.Predicate.Recipe.Arguments = event.Inputs
-			stmt.Predicate.Materials = append(stmt.Predicate.Materials, intoto.Item{URI: "git+" + repoURI, Digest: intoto.DigestSet{"sha1": gh.SHA}})
+			builderIDValue, invocation, rawToken, err := builderIdentity(*useOIDC, repoURI)
+			if err != nil {
+				return err
+			}
+
+			var payload []byte
+			switch *predicateVersion {
+			case predicateVersionV1:
+				stmt := slsaProvenanceV1Statement(subjects, builderIDValue, repoURI, gh, event, invocation)
+				payload, _ = json.MarshalIndent(stmt, "", "  ")
+			default:
+				stmt := intoto.SLSAProvenanceStatement(
+					intoto.WithSubject(subjects),
+					intoto.WithBuilder(builderIDValue),
+					intoto.WithMetadata(repoURI+"/actions/runs/"+gh.RunID),
+					intoto.WithInvocation(invocation),
+				)
+
+				stmt.Predicate.Recipe = intoto.Recipe{
+					Type:              typeID,
+					DefinedInMaterial: 0,
+				}
+				stmt.Predicate.Materials = []intoto.Item{}
+
+				// NOTE: This is inexact as multiple workflows in a repo can have the same name.
+				// See https://github.com/github/feedback/discussions/4188
+				stmt.Predicate.Recipe.EntryPoint = gh.Workflow
+				stmt.Predicate.Recipe.Arguments = event.Inputs
+				stmt.Predicate.Materials = append(stmt.Predicate.Materials, intoto.Item{URI: "git+" + repoURI, Digest: intoto.DigestSet{"sha1": gh.SHA}})
 
-			// NOTE: At L1, writing the in-toto Statement type is sufficient but, at
-			// higher SLSA levels, the Statement must be encoded and wrapped in an
-			// Envelope to support attaching signatures.
-			payload, _ := json.MarshalIndent(stmt, "", "  ")
+				// NOTE: At L1, writing the in-toto Statement type is sufficient but, at
+				// higher SLSA levels, the Statement must be encoded and wrapped in an
+				// Envelope to support attaching signatures.
+				payload, _ = json.MarshalIndent(stmt, "", "  ")
+			}
 			fmt.Fprintf(w, "Saving provenance to %s:\n\n%s\n", *outputPath, string(payload))
 
 			if err := os.WriteFile(*outputPath, payload, 0755); err != nil {
 				return errors.Wrap(err, "failed to write provenance")
 			}
 
+			if *useOIDC {
+				tokenPath := *outputPath + ".oidc-token"
+				fmt.Fprintf(w, "Saving raw OIDC token to %s\n", tokenPath)
+				if err := os.WriteFile(tokenPath, []byte(rawToken), 0600); err != nil {
+					return errors.Wrap(err, "failed to write OIDC token")
+				}
+			}
+
+			if *sign {
+				signer, err := dsse.NewECDSASignerFromFile(*signingKey)
+				if err != nil {
+					return errors.Wrap(err, "failed to load signing key")
+				}
+
+				line, _, err := signEnvelope(payload, signer, nil)
+				if err != nil {
+					return err
+				}
+
+				envelopePath := *outputPath + ".intoto.jsonl"
+				fmt.Fprintf(w, "Saving signed provenance to %s\n", envelopePath)
+				if err := os.WriteFile(envelopePath, line, 0755); err != nil {
+					return errors.Wrap(err, "failed to write signed envelope")
+				}
+			}
+
 			return nil
 		},
 	}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSubjectsFromSHA256Sum(t *testing.T) {
+	raw := "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae  foo.tar.gz\n" +
+		"fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9  bar.tar.gz\n"
+	encoded := base64.StdEncoding.EncodeToString([]byte(raw))
+
+	subjects, err := subjectsFromSHA256Sum(encoded)
+	if err != nil {
+		t.Fatalf("subjectsFromSHA256Sum() error = %v", err)
+	}
+	if len(subjects) != 2 {
+		t.Fatalf("len(subjects) = %d, want 2", len(subjects))
+	}
+	if subjects[0].Name != "foo.tar.gz" || subjects[0].Digest["sha256"] != "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae" {
+		t.Errorf("subjects[0] = %+v, want foo.tar.gz with matching sha256", subjects[0])
+	}
+}
+
+func TestSubjectsFromSHA256SumRejectsBadDigest(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("not-a-digest  foo.tar.gz\n"))
+
+	if _, err := subjectsFromSHA256Sum(encoded); err == nil {
+		t.Fatal("expected an error for a malformed sha256 digest, got nil")
+	}
+}
+
+func TestSubjectsFromSHA256SumRejectsDuplicateNames(t *testing.T) {
+	raw := "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae  foo.tar.gz\n" +
+		"fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9  foo.tar.gz\n"
+	encoded := base64.StdEncoding.EncodeToString([]byte(raw))
+
+	if _, err := subjectsFromSHA256Sum(encoded); err == nil {
+		t.Fatal("expected an error for a duplicate subject name, got nil")
+	}
+}
+
+func TestSubjectsFromPath(t *testing.T) {
+	dir := t.TempDir()
+	for i, name := range []string{"b.txt", "a.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte{byte(i)}, 0644); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", path, err)
+		}
+	}
+
+	algos, err := parseDigestAlgorithms("sha256,sha512")
+	if err != nil {
+		t.Fatalf("parseDigestAlgorithms() error = %v", err)
+	}
+
+	subjects, err := subjectsFromPath(dir, 2, algos)
+	if err != nil {
+		t.Fatalf("subjectsFromPath() error = %v", err)
+	}
+	if len(subjects) != 2 {
+		t.Fatalf("len(subjects) = %d, want 2", len(subjects))
+	}
+	if subjects[0].Name != "a.txt" || subjects[1].Name != "b.txt" {
+		t.Errorf("subjects = [%s, %s], want deterministic [a.txt, b.txt] ordering", subjects[0].Name, subjects[1].Name)
+	}
+	for _, subject := range subjects {
+		if _, ok := subject.Digest["sha256"]; !ok {
+			t.Errorf("subject %q missing sha256 digest", subject.Name)
+		}
+		if _, ok := subject.Digest["sha512"]; !ok {
+			t.Errorf("subject %q missing sha512 digest", subject.Name)
+		}
+	}
+}
+
+func TestParseDigestAlgorithmsRejectsUnknown(t *testing.T) {
+	if _, err := parseDigestAlgorithms("sha256,md5"); err == nil {
+		t.Fatal("expected an error for an unsupported digest algorithm, got nil")
+	}
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"github.com/pkg/errors"
+
+	"github.com/philips-labs/slsa-provenance-action/lib/dsse"
+	"github.com/philips-labs/slsa-provenance-action/lib/fulcio"
+	"github.com/philips-labs/slsa-provenance-action/lib/github"
+	"github.com/philips-labs/slsa-provenance-action/lib/rekor"
+)
+
+// Sign creates an instance of *ffcli.Command to wrap a generated in-toto
+// Statement in a signed DSSE envelope, producing a
+// build.provenance.intoto.jsonl-style file with one envelope per line.
+func Sign(w io.Writer) *ffcli.Command {
+	var (
+		flagset        = flag.NewFlagSet("slsa-provenance sign", flag.ExitOnError)
+		provenancePath = flagset.String("provenance_path", "build.provenance", "The path of the in-toto Statement to sign.")
+		outputPath     = flagset.String("output_path", "build.provenance.intoto.jsonl", "The path to which the signed DSSE envelope should be appended, one per line.")
+		key            = flagset.String("key", "", "Path to a generic, unencrypted PEM-encoded ECDSA private key to sign with (e.g. from `openssl ecparam -genkey`). cosign.key is always password-protected and not yet supported. Mutually exclusive with -fulcio and -kms.")
+		kmsURI         = flagset.String("kms", "", "A KMS URI (e.g. awskms://...) to sign with. Not yet supported: this repo does not vendor a cloud KMS SDK, so -fulcio keyless signing is the supported alternative to a local -key.")
+		useFulcio      = flagset.Bool("fulcio", false, "Sign keylessly: request a short-lived certificate from Fulcio for the ambient GitHub Actions OIDC token, sign with a fresh ephemeral key, and upload the entry to Rekor. Mutually exclusive with -key and -kms.")
+		fulcioURL      = flagset.String("fulcio-url", fulcio.DefaultURL, "The Fulcio instance to request a signing certificate from. Only used with -fulcio.")
+		rekorURL       = flagset.String("rekor-url", rekor.DefaultURL, "The Rekor instance to upload the transparency log entry to. Only used with -fulcio.")
+	)
+
+	flagset.SetOutput(w)
+
+	return &ffcli.Command{
+		Name:       "sign",
+		ShortUsage: "slsa-provenance sign",
+		ShortHelp:  "Wraps a generated provenance statement in a signed DSSE envelope",
+		FlagSet:    flagset,
+		Exec: func(ctx context.Context, args []string) error {
+			if *kmsURI != "" {
+				return fmt.Errorf("-kms signing is not yet supported")
+			}
+			if *useFulcio && *key != "" {
+				flagset.Usage()
+				return fmt.Errorf("-fulcio and -key are mutually exclusive")
+			}
+			if !*useFulcio && *key == "" {
+				flagset.Usage()
+				return RequiredFlagError("-key")
+			}
+
+			payload, err := os.ReadFile(*provenancePath)
+			if err != nil {
+				return errors.Wrap(err, "failed to read provenance")
+			}
+
+			var (
+				signer  dsse.Signer
+				certPEM []byte
+			)
+			if *useFulcio {
+				signer, certPEM, err = fulcioSigner(*fulcioURL)
+				if err != nil {
+					return err
+				}
+			} else {
+				signer, err = dsse.NewECDSASignerFromFile(*key)
+				if err != nil {
+					return errors.Wrap(err, "failed to load signing key")
+				}
+			}
+
+			line, env, err := signEnvelope(payload, signer, certPEM)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(w, "Saving signed provenance to %s\n", *outputPath)
+			f, err := os.OpenFile(*outputPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0755)
+			if err != nil {
+				return errors.Wrap(err, "failed to open signed envelope output")
+			}
+			if _, err := f.Write(line); err != nil {
+				f.Close()
+				return errors.Wrap(err, "failed to write signed envelope")
+			}
+			if err := f.Close(); err != nil {
+				return errors.Wrap(err, "failed to write signed envelope")
+			}
+
+			if *useFulcio {
+				return uploadToRekor(w, *rekorURL, *outputPath, env, payload)
+			}
+			return nil
+		},
+	}
+}
+
+// fulcioSigner generates an ephemeral signing key, exchanges the ambient
+// GitHub Actions OIDC token for a Fulcio signing certificate bound to it,
+// and returns a Signer wrapping the key alongside the PEM certificate chain
+// to embed in the envelope.
+func fulcioSigner(fulcioURL string) (dsse.Signer, []byte, error) {
+	key, err := fulcio.GenerateKeyPair()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oidcToken, claims, err := github.RequestOIDCToken("sigstore")
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to obtain OIDC token")
+	}
+
+	certPEM, err := fulcio.RequestCertificate(fulcioURL, oidcToken, claims.Subject, key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to obtain Fulcio certificate")
+	}
+
+	return dsse.NewECDSASignerFromKey(key), certPEM, nil
+}
+
+// uploadToRekor uploads env's signature, together with the DSSE PAE of the
+// payload it was computed over, to the Rekor instance at rekorURL as a
+// hashedrekord entry, writing the resulting log entry
+// (including its inclusion proof) alongside outputPath for offline
+// verification later.
+func uploadToRekor(w io.Writer, rekorURL, outputPath string, env *dsse.Envelope, payload []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(env.Signatures[0].Sig)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode envelope signature")
+	}
+
+	entry, err := rekor.UploadHashedRekord(rekorURL, []byte(env.Signatures[0].Cert), sig, dsse.PAE(env.PayloadType, payload))
+	if err != nil {
+		return errors.Wrap(err, "failed to upload entry to Rekor")
+	}
+
+	entryPath := outputPath + ".rekor.json"
+	raw, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal Rekor entry")
+	}
+
+	fmt.Fprintf(w, "Uploaded Rekor entry %s (log index %d) to %s\n", entry.UUID, entry.LogIndex, entryPath)
+	return errors.Wrap(os.WriteFile(entryPath, raw, 0755), "failed to write Rekor entry")
+}
+
+// signEnvelope wraps payload in a signed DSSE envelope, embedding certPEM
+// as the signature's certificate chain when keyless signing is used, and
+// returns it as a single JSON line ready to be appended to a
+// build.provenance.intoto.jsonl file, alongside the parsed Envelope.
+func signEnvelope(payload []byte, signer dsse.Signer, certPEM []byte) ([]byte, *dsse.Envelope, error) {
+	env, err := dsse.Sign(dsse.PayloadTypeInToto, payload, signer)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to sign provenance")
+	}
+	if len(certPEM) > 0 {
+		env.Signatures[0].Cert = string(certPEM)
+	}
+
+	line, err := json.Marshal(env)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal envelope")
+	}
+	return append(line, '\n'), env, nil
+}
@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/philips-labs/slsa-provenance-action/lib/dsse"
+)
+
+func writeECKey(t *testing.T, dir, name string) (path string, key *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+
+	path = filepath.Join(dir, name)
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, pemBytes, 0600); err != nil {
+		t.Fatalf("WriteFile(%s) error = %v", path, err)
+	}
+	return path, key
+}
+
+func TestSignWithKey(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, key := writeECKey(t, dir, "signer.key")
+
+	provenancePath := filepath.Join(dir, "build.provenance")
+	if err := os.WriteFile(provenancePath, []byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`), 0600); err != nil {
+		t.Fatalf("WriteFile(%s) error = %v", provenancePath, err)
+	}
+	outputPath := filepath.Join(dir, "build.provenance.intoto.jsonl")
+
+	var w bytes.Buffer
+	cmd := Sign(&w)
+	if err := cmd.ParseAndRun(context.Background(), []string{"-key", keyPath, "-provenance_path", provenancePath, "-output_path", outputPath}); err != nil {
+		t.Fatalf("sign -key ParseAndRun() error = %v", err)
+	}
+
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", outputPath, err)
+	}
+
+	var env dsse.Envelope
+	if err := json.Unmarshal(bytes.TrimSpace(raw), &env); err != nil {
+		t.Fatalf("failed to unmarshal signed envelope: %v", err)
+	}
+
+	if err := dsse.Verify(&env, dsse.NewECDSAVerifierFromKey(&key.PublicKey)); err != nil {
+		t.Errorf("dsse.Verify() error = %v, want a valid signature", err)
+	}
+}
+
+func TestSignRequiresKeyOrFulcio(t *testing.T) {
+	var w bytes.Buffer
+	cmd := Sign(&w)
+	if err := cmd.ParseAndRun(context.Background(), nil); err == nil {
+		t.Fatal("expected an error when neither -key nor -fulcio is set, got nil")
+	}
+}
+
+func TestSignRejectsKeyAndFulcioTogether(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, _ := writeECKey(t, dir, "signer.key")
+
+	var w bytes.Buffer
+	cmd := Sign(&w)
+	if err := cmd.ParseAndRun(context.Background(), []string{"-key", keyPath, "-fulcio"}); err == nil {
+		t.Fatal("expected an error when -key and -fulcio are both set, got nil")
+	}
+}
+
+// fulcioClaimsToken builds a minimal JWT whose payload segment carries the
+// given "sub" claim; RequestOIDCToken's caller only ever inspects the
+// payload segment, so the header and signature segments are placeholders.
+func fulcioClaimsToken(t *testing.T, subject string) string {
+	t.Helper()
+
+	payload, err := json.Marshal(struct {
+		Subject string `json:"sub"`
+	}{Subject: subject})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	return "header." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+// issueFulcioLeafCert mimics Fulcio issuing a certificate that binds pub (the
+// ephemeral public key submitted in the signing request), the way a real
+// Fulcio instance would.
+func issueFulcioLeafCert(t *testing.T, pub *ecdsa.PublicKey, sanURI string) []byte {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	uri, err := url.Parse(sanURI)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{uri},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestSignWithFulcio(t *testing.T) {
+	const subject = "repo:owner/repo:ref:refs/heads/main"
+	token := fulcioClaimsToken(t, subject)
+
+	oidcSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"value":%q}`, token)
+	}))
+	defer oidcSrv.Close()
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", oidcSrv.URL)
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "ambient-token")
+
+	fulcioSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			PublicKeyRequest struct {
+				PublicKey struct {
+					Content string `json:"content"`
+				} `json:"publicKey"`
+			} `json:"publicKeyRequest"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode Fulcio signing request: %v", err)
+		}
+		pubDER, err := base64.StdEncoding.DecodeString(body.PublicKeyRequest.PublicKey.Content)
+		if err != nil {
+			t.Fatalf("failed to decode submitted public key: %v", err)
+		}
+		pub, err := x509.ParsePKIXPublicKey(pubDER)
+		if err != nil {
+			t.Fatalf("failed to parse submitted public key: %v", err)
+		}
+		leafPEM := issueFulcioLeafCert(t, pub.(*ecdsa.PublicKey), "https://github.com/owner/repo/.github/workflows/release.yml@refs/heads/main")
+
+		raw, err := json.Marshal([]string{string(leafPEM)})
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		fmt.Fprintf(w, `{"signedCertificateEmbeddedSct":{"chain":{"certificates":%s}}}`, raw)
+	}))
+	defer fulcioSrv.Close()
+
+	rekorSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := base64.StdEncoding.EncodeToString([]byte("entry"))
+		fmt.Fprintf(w, `{"deadbeef":{"logIndex":1,"integratedTime":1700000000,"body":%q,"verification":{"inclusionProof":{"logIndex":0,"treeSize":1,"rootHash":"","hashes":[]}}}}`, body)
+	}))
+	defer rekorSrv.Close()
+
+	dir := t.TempDir()
+	provenancePath := filepath.Join(dir, "build.provenance")
+	if err := os.WriteFile(provenancePath, []byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`), 0600); err != nil {
+		t.Fatalf("WriteFile(%s) error = %v", provenancePath, err)
+	}
+	outputPath := filepath.Join(dir, "build.provenance.intoto.jsonl")
+
+	var w bytes.Buffer
+	cmd := Sign(&w)
+	err := cmd.ParseAndRun(context.Background(), []string{
+		"-fulcio",
+		"-fulcio-url", fulcioSrv.URL,
+		"-rekor-url", rekorSrv.URL,
+		"-provenance_path", provenancePath,
+		"-output_path", outputPath,
+	})
+	if err != nil {
+		t.Fatalf("sign -fulcio ParseAndRun() error = %v", err)
+	}
+
+	raw, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", outputPath, err)
+	}
+	var env dsse.Envelope
+	if err := json.Unmarshal(bytes.TrimSpace(raw), &env); err != nil {
+		t.Fatalf("failed to unmarshal signed envelope: %v", err)
+	}
+	if env.Signatures[0].Cert == "" {
+		t.Fatal("signed envelope is missing the embedded certificate chain")
+	}
+
+	block, _ := pem.Decode([]byte(env.Signatures[0].Cert))
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse embedded leaf certificate: %v", err)
+	}
+	if err := dsse.Verify(&env, dsse.NewECDSAVerifierFromKey(leaf.PublicKey.(*ecdsa.PublicKey))); err != nil {
+		t.Errorf("dsse.Verify() error = %v, want a valid signature under the embedded leaf certificate's key", err)
+	}
+
+	if _, err := os.Stat(outputPath + ".rekor.json"); err != nil {
+		t.Errorf("expected a Rekor bundle to be written alongside %s: %v", outputPath, err)
+	}
+}
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"github.com/pkg/errors"
+
+	"github.com/philips-labs/slsa-provenance-action/lib/dsse"
+	"github.com/philips-labs/slsa-provenance-action/lib/fulcio"
+	"github.com/philips-labs/slsa-provenance-action/lib/rekor"
+)
+
+// Verify creates an instance of *ffcli.Command to verify a signed DSSE
+// envelope file produced by Sign.
+func Verify(w io.Writer) *ffcli.Command {
+	var (
+		flagset           = flag.NewFlagSet("slsa-provenance verify", flag.ExitOnError)
+		envelopePath      = flagset.String("envelope_path", "build.provenance.intoto.jsonl", "The path of the signed DSSE envelope(s) to verify.")
+		publicKey         = flagset.String("key", "", "Path to the PEM-encoded ECDSA public key to verify a locally-signed envelope against.")
+		fulcioRootPath    = flagset.String("fulcio-root", "", "Path to a PEM bundle of Fulcio CA certificate(s), required to verify a keylessly-signed envelope.")
+		expectWorkflowRef = flagset.String("expect-workflow-ref", "", "If set, require a keyless envelope's certificate SAN to match this exact workflow ref URI (e.g. 'https://github.com/owner/repo/.github/workflows/release.yml@refs/heads/main').")
+		rekorBundlePath   = flagset.String("rekor-bundle", "", "Path to the .rekor.json entry written by 'sign -fulcio'. If set, its inclusion proof is verified offline.")
+	)
+
+	flagset.SetOutput(w)
+
+	return &ffcli.Command{
+		Name:       "verify",
+		ShortUsage: "slsa-provenance verify",
+		ShortHelp:  "Verifies a signed DSSE envelope",
+		FlagSet:    flagset,
+		Exec: func(ctx context.Context, args []string) error {
+			if *publicKey == "" && *fulcioRootPath == "" {
+				flagset.Usage()
+				return fmt.Errorf("at least one of -key or -fulcio-root must be set")
+			}
+
+			var verifier dsse.Verifier
+			if *publicKey != "" {
+				v, err := dsse.NewECDSAVerifierFromFile(*publicKey)
+				if err != nil {
+					return errors.Wrap(err, "failed to load verification key")
+				}
+				verifier = v
+			}
+
+			var fulcioRoots *x509.CertPool
+			if *fulcioRootPath != "" {
+				raw, err := os.ReadFile(*fulcioRootPath)
+				if err != nil {
+					return errors.Wrap(err, "failed to read -fulcio-root")
+				}
+				fulcioRoots = x509.NewCertPool()
+				if !fulcioRoots.AppendCertsFromPEM(raw) {
+					return fmt.Errorf("no certificates found in -fulcio-root")
+				}
+			}
+
+			raw, err := os.ReadFile(*envelopePath)
+			if err != nil {
+				return errors.Wrap(err, "failed to read envelope")
+			}
+
+			// A Fulcio certificate is only valid for ~10 minutes, so by the
+			// time verification happens it has normally expired. When a
+			// Rekor bundle is available, its IntegratedTime is the moment
+			// the signature was actually created and known-good, so chain
+			// verification is pinned to it instead of the wall clock.
+			var rekorEntry *rekor.Entry
+			var certTime time.Time
+			if *rekorBundlePath != "" {
+				rekorEntry, err = readRekorBundle(*rekorBundlePath)
+				if err != nil {
+					return err
+				}
+				certTime = time.Unix(rekorEntry.IntegratedTime, 0)
+			}
+
+			n, err := verifyEnvelopes(raw, verifier, fulcioRoots, *expectWorkflowRef, certTime)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "Verified %d signed envelope(s) in %s\n", n, *envelopePath)
+
+			if rekorEntry != nil {
+				if err := rekor.VerifyInclusionProof(rekorEntry); err != nil {
+					return errors.Wrap(err, "failed to verify Rekor inclusion proof")
+				}
+				fmt.Fprintf(w, "Verified Rekor inclusion proof for entry %s (log index %d)\n", rekorEntry.UUID, rekorEntry.LogIndex)
+			}
+
+			return nil
+		},
+	}
+}
+
+// verifyEnvelopes verifies every DSSE envelope in raw (one JSON object per
+// line). Envelopes carrying a keyless (Fulcio) certificate are verified
+// against fulcioRoots as of certTime (the zero Time means the current wall
+// clock) and, if expectWorkflowRef is set, against the certificate's SAN
+// workflow identity; envelopes without a certificate are verified against
+// verifier. It returns the number of envelopes verified.
+func verifyEnvelopes(raw []byte, verifier dsse.Verifier, fulcioRoots *x509.CertPool, expectWorkflowRef string, certTime time.Time) (int, error) {
+	n := 0
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		n++
+
+		var env dsse.Envelope
+		if err := json.Unmarshal(line, &env); err != nil {
+			return 0, errors.Wrapf(err, "failed to unmarshal envelope on line %d", n)
+		}
+
+		envVerifier := verifier
+		if len(env.Signatures) > 0 && env.Signatures[0].Cert != "" {
+			v, err := verifyKeylessIdentity([]byte(env.Signatures[0].Cert), fulcioRoots, expectWorkflowRef, certTime)
+			if err != nil {
+				return 0, errors.Wrapf(err, "failed to verify keyless identity on line %d", n)
+			}
+			envVerifier = v
+		} else if envVerifier == nil {
+			return 0, fmt.Errorf("envelope on line %d is not keyless and -key was not set", n)
+		}
+
+		if err := dsse.Verify(&env, envVerifier); err != nil {
+			return 0, errors.Wrapf(err, "signature verification failed on line %d", n)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, errors.Wrap(err, "failed to scan envelope file")
+	}
+
+	return n, nil
+}
+
+// verifyKeylessIdentity chain-verifies certPEM against fulcioRoots as of
+// certTime (the zero Time means the current wall clock, which a short-lived
+// Fulcio certificate will usually have already outlived by the time
+// verification runs), checks its SAN workflow identity against
+// expectWorkflowRef when set, and returns a Verifier for its leaf public
+// key.
+func verifyKeylessIdentity(certPEM []byte, fulcioRoots *x509.CertPool, expectWorkflowRef string, certTime time.Time) (dsse.Verifier, error) {
+	if fulcioRoots == nil {
+		return nil, fmt.Errorf("envelope has a keyless signature but -fulcio-root was not provided")
+	}
+
+	block, rest := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block from envelope certificate")
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse envelope certificate")
+	}
+
+	// The chain Fulcio issued alongside the leaf (embedded by sign -fulcio)
+	// carries any intermediate CA needed to build up to -fulcio-root, so it
+	// is decoded here and offered to Verify rather than requiring
+	// -fulcio-root to bundle the intermediate itself.
+	intermediates := x509.NewCertPool()
+	for {
+		var interBlock *pem.Block
+		interBlock, rest = pem.Decode(rest)
+		if interBlock == nil {
+			break
+		}
+		inter, err := x509.ParseCertificate(interBlock.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse intermediate certificate")
+		}
+		intermediates.AddCert(inter)
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: fulcioRoots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}, CurrentTime: certTime}); err != nil {
+		return nil, errors.Wrap(err, "failed to verify certificate chain against -fulcio-root")
+	}
+
+	identity, err := fulcio.ParseIdentity(certPEM)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse certificate identity")
+	}
+	if expectWorkflowRef != "" && identity.WorkflowRefURI != expectWorkflowRef {
+		return nil, fmt.Errorf("certificate identity %q does not match -expect-workflow-ref %q", identity.WorkflowRefURI, expectWorkflowRef)
+	}
+
+	pub, ok := leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("certificate public key is not ECDSA")
+	}
+	return dsse.NewECDSAVerifierFromKey(pub), nil
+}
+
+// readRekorBundle loads and unmarshals the Rekor entry at path.
+func readRekorBundle(path string) (*rekor.Entry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read -rekor-bundle")
+	}
+
+	var entry rekor.Entry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal -rekor-bundle")
+	}
+
+	return &entry, nil
+}
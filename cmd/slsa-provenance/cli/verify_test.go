@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/philips-labs/slsa-provenance-action/lib/dsse"
+)
+
+func writeECPubKey(t *testing.T, dir, name string, pub *ecdsa.PublicKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+	}
+
+	path := filepath.Join(dir, name)
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	if err := os.WriteFile(path, pemBytes, 0600); err != nil {
+		t.Fatalf("WriteFile(%s) error = %v", path, err)
+	}
+	return path
+}
+
+func writeEnvelope(t *testing.T, dir, name string, env *dsse.Envelope) string {
+	t.Helper()
+
+	raw, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, append(raw, '\n'), 0600); err != nil {
+		t.Fatalf("WriteFile(%s) error = %v", path, err)
+	}
+	return path
+}
+
+func TestVerifyWithKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	env, err := dsse.Sign(dsse.PayloadTypeInToto, []byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`), dsse.NewECDSASignerFromKey(key))
+	if err != nil {
+		t.Fatalf("dsse.Sign() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	pubKeyPath := writeECPubKey(t, dir, "signer.pub", &key.PublicKey)
+	envelopePath := writeEnvelope(t, dir, "build.provenance.intoto.jsonl", env)
+
+	var w bytes.Buffer
+	cmd := Verify(&w)
+	if err := cmd.ParseAndRun(context.Background(), []string{"-key", pubKeyPath, "-envelope_path", envelopePath}); err != nil {
+		t.Fatalf("verify -key ParseAndRun() error = %v", err)
+	}
+}
+
+func TestVerifyRejectsTamperedEnvelope(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	env, err := dsse.Sign(dsse.PayloadTypeInToto, []byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`), dsse.NewECDSASignerFromKey(key))
+	if err != nil {
+		t.Fatalf("dsse.Sign() error = %v", err)
+	}
+	env.Payload = base64.StdEncoding.EncodeToString([]byte(`{"_type":"tampered"}`))
+
+	dir := t.TempDir()
+	pubKeyPath := writeECPubKey(t, dir, "signer.pub", &key.PublicKey)
+	envelopePath := writeEnvelope(t, dir, "build.provenance.intoto.jsonl", env)
+
+	var w bytes.Buffer
+	cmd := Verify(&w)
+	if err := cmd.ParseAndRun(context.Background(), []string{"-key", pubKeyPath, "-envelope_path", envelopePath}); err == nil {
+		t.Fatal("expected an error verifying a tampered envelope, got nil")
+	}
+}
+
+// certChain is a 3-certificate Fulcio-style chain: a root CA, an
+// intermediate CA it issued, and a leaf code-signing cert the intermediate
+// issued. Callers bundle only rootPEM as -fulcio-root, mirroring how a real
+// Fulcio root bundle never ships the per-request intermediate.
+type certChain struct {
+	rootPEM, leafChainPEM []byte
+	leafKey               *ecdsa.PrivateKey
+}
+
+func buildFulcioStyleCertChain(t *testing.T, sanURI string) certChain {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate(root) error = %v", err)
+	}
+	rootCert, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatalf("ParseCertificate(root) error = %v", err)
+	}
+	rootPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDER})
+
+	interKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	interTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	interDER, err := x509.CreateCertificate(rand.Reader, interTemplate, rootCert, &interKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate(intermediate) error = %v", err)
+	}
+	interCert, err := x509.ParseCertificate(interDER)
+	if err != nil {
+		t.Fatalf("ParseCertificate(intermediate) error = %v", err)
+	}
+	interPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: interDER})
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	uri, err := url.Parse(sanURI)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{uri},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, interCert, &leafKey.PublicKey, interKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate(leaf) error = %v", err)
+	}
+	leafPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})
+
+	return certChain{
+		rootPEM:      rootPEM,
+		leafChainPEM: append(append([]byte{}, leafPEM...), interPEM...),
+		leafKey:      leafKey,
+	}
+}
+
+func TestVerifyKeylessWithEmbeddedIntermediate(t *testing.T) {
+	const workflowRef = "https://github.com/owner/repo/.github/workflows/release.yml@refs/heads/main"
+	chain := buildFulcioStyleCertChain(t, workflowRef)
+
+	env, err := dsse.Sign(dsse.PayloadTypeInToto, []byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`), dsse.NewECDSASignerFromKey(chain.leafKey))
+	if err != nil {
+		t.Fatalf("dsse.Sign() error = %v", err)
+	}
+	env.Signatures[0].Cert = string(chain.leafChainPEM)
+
+	dir := t.TempDir()
+	fulcioRootPath := filepath.Join(dir, "fulcio-root.pem")
+	if err := os.WriteFile(fulcioRootPath, chain.rootPEM, 0600); err != nil {
+		t.Fatalf("WriteFile(%s) error = %v", fulcioRootPath, err)
+	}
+	envelopePath := writeEnvelope(t, dir, "build.provenance.intoto.jsonl", env)
+
+	var w bytes.Buffer
+	cmd := Verify(&w)
+	err = cmd.ParseAndRun(context.Background(), []string{
+		"-fulcio-root", fulcioRootPath,
+		"-expect-workflow-ref", workflowRef,
+		"-envelope_path", envelopePath,
+	})
+	if err != nil {
+		t.Fatalf("verify ParseAndRun() error = %v, want the embedded intermediate to complete the chain to -fulcio-root", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Package dsse implements the Dead Simple Signing Envelope format
+// (https://github.com/secure-systems-lab/dsse), used to wrap in-toto
+// Statements with one or more signatures so provenance can reach SLSA L2/L3.
+package dsse
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// PayloadTypeInToto is the DSSE payloadType used for in-toto Statements.
+const PayloadTypeInToto = "application/vnd.in-toto+json"
+
+// Envelope is a DSSE envelope: a payload plus one or more signatures over it.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a single signature over an Envelope's payload.
+type Signature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   string `json:"sig"`
+	// Cert is the PEM-encoded signing certificate chain, present for
+	// keyless (Fulcio-issued) signatures.
+	Cert string `json:"cert,omitempty"`
+}
+
+// Signer produces a raw signature over an arbitrary message.
+type Signer interface {
+	Sign(message []byte) ([]byte, error)
+	KeyID() (string, error)
+}
+
+// Verifier checks a raw signature over an arbitrary message.
+type Verifier interface {
+	Verify(message, sig []byte) error
+}
+
+// PAE computes the DSSE v1 pre-authentication encoding of a payload:
+//
+//	PAE(type, body) = "DSSEv1" + SP + LEN(type) + SP + type + SP + LEN(body) + SP + body
+func PAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// Sign wraps payload in a DSSE Envelope signed by signer.
+func Sign(payloadType string, payload []byte, signer Signer) (*Envelope, error) {
+	sig, err := signer.Sign(PAE(payloadType, payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign payload")
+	}
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine signer key id")
+	}
+
+	return &Envelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []Signature{
+			{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)},
+		},
+	}, nil
+}
+
+// Verify checks that at least one signature in env is valid for verifier.
+func Verify(env *Envelope, verifier Verifier) error {
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode payload")
+	}
+	pae := PAE(env.PayloadType, payload)
+
+	if len(env.Signatures) == 0 {
+		return fmt.Errorf("envelope has no signatures")
+	}
+
+	var lastErr error
+	for _, sig := range env.Signatures {
+		raw, err := base64.StdEncoding.DecodeString(sig.Sig)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := verifier.Verify(pae, raw); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	return errors.Wrap(lastErr, "no valid signature found")
+}
@@ -0,0 +1,76 @@
+package dsse
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+type testSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *testSigner) Sign(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	return ecdsa.SignASN1(rand.Reader, s.key, digest[:])
+}
+
+func (s *testSigner) KeyID() (string, error) { return "test-key", nil }
+
+type testVerifier struct {
+	pub *ecdsa.PublicKey
+}
+
+func (v *testVerifier) Verify(message, sig []byte) error {
+	digest := sha256.Sum256(message)
+	if !ecdsa.VerifyASN1(v.pub, digest[:], sig) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
+
+func TestPAE(t *testing.T) {
+	got := string(PAE("application/vnd.in-toto+json", []byte("hello")))
+	want := "DSSEv1 28 application/vnd.in-toto+json 5 hello"
+	if got != want {
+		t.Errorf("PAE() = %q, want %q", got, want)
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	payload := []byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`)
+	env, err := Sign(PayloadTypeInToto, payload, &testSigner{key: key})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	if err := Verify(env, &testVerifier{pub: &key.PublicKey}); err != nil {
+		t.Errorf("Verify() error = %v, want nil", err)
+	}
+}
+
+func TestVerifyRejectsTamperedPayload(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	env, err := Sign(PayloadTypeInToto, []byte("original"), &testSigner{key: key})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	env.Payload = "dGFtcGVyZWQ=" // base64("tampered")
+
+	if err := Verify(env, &testVerifier{pub: &key.PublicKey}); err == nil {
+		t.Error("Verify() error = nil, want error for tampered payload")
+	}
+}
@@ -0,0 +1,170 @@
+// Package fulcio implements the minimal subset of the Sigstore Fulcio
+// keyless-signing protocol needed to request a short-lived code-signing
+// certificate for an ephemeral key, binding it to the identity carried by
+// an ambient GitHub Actions OIDC token, and to read that identity back out
+// of the issued certificate.
+package fulcio
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultURL is the public Sigstore Fulcio instance.
+const DefaultURL = "https://fulcio.sigstore.dev"
+
+// oidIssuer is the Fulcio certificate extension carrying the OIDC issuer
+// that authenticated the signing identity.
+// See https://github.com/sigstore/fulcio/blob/main/docs/oid-info.md
+var oidIssuer = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+
+// Identity is the workflow identity embedded in a Fulcio-issued certificate
+// for a GitHub Actions signing event.
+type Identity struct {
+	// Issuer is the OIDC issuer that authenticated the request, e.g.
+	// "https://token.actions.githubusercontent.com".
+	Issuer string
+	// WorkflowRefURI is the certificate's SAN URI, which for GitHub
+	// Actions identifies the exact workflow invocation, e.g.
+	// "https://github.com/owner/repo/.github/workflows/release.yml@refs/heads/main".
+	WorkflowRefURI string
+}
+
+// GenerateKeyPair creates a fresh P-256 ECDSA key pair to use as the
+// ephemeral signing key bound to a Fulcio certificate.
+func GenerateKeyPair() (*ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate ephemeral signing key")
+	}
+	return key, nil
+}
+
+// RequestCertificate asks the Fulcio instance at fulcioURL to issue a
+// short-lived code-signing certificate binding key to the identity carried
+// by oidcToken, returning the PEM-encoded certificate chain (leaf first).
+// subject is the OIDC token's "sub" claim, over which the proof of
+// possession is computed.
+func RequestCertificate(fulcioURL, oidcToken, subject string, key *ecdsa.PrivateKey) ([]byte, error) {
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal ephemeral public key")
+	}
+
+	// Proof of possession: sign the OIDC token's "sub" claim with the
+	// ephemeral key, demonstrating the requester holds the private key that
+	// the issued certificate will be bound to. Fulcio verifies this against
+	// the subject it parses from the token itself, not the raw token text.
+	digest := sha256.Sum256([]byte(subject))
+	proof, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute proof of possession")
+	}
+
+	body, err := json.Marshal(struct {
+		Credentials struct {
+			OIDCIdentityToken string `json:"oidcIdentityToken"`
+		} `json:"credentials"`
+		PublicKeyRequest struct {
+			PublicKey struct {
+				Algorithm string `json:"algorithm"`
+				Content   string `json:"content"`
+			} `json:"publicKey"`
+			ProofOfPossession string `json:"proofOfPossession"`
+		} `json:"publicKeyRequest"`
+	}{
+		Credentials: struct {
+			OIDCIdentityToken string `json:"oidcIdentityToken"`
+		}{OIDCIdentityToken: oidcToken},
+		PublicKeyRequest: struct {
+			PublicKey struct {
+				Algorithm string `json:"algorithm"`
+				Content   string `json:"content"`
+			} `json:"publicKey"`
+			ProofOfPossession string `json:"proofOfPossession"`
+		}{
+			PublicKey: struct {
+				Algorithm string `json:"algorithm"`
+				Content   string `json:"content"`
+			}{Algorithm: "ECDSA", Content: base64.StdEncoding.EncodeToString(pubDER)},
+			ProofOfPossession: base64.StdEncoding.EncodeToString(proof),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal Fulcio signing request")
+	}
+
+	resp, err := http.Post(strings.TrimSuffix(fulcioURL, "/")+"/api/v2/signingCert", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request Fulcio certificate")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("Fulcio certificate request failed: %s", resp.Status)
+	}
+
+	var parsed struct {
+		SignedCertificateEmbeddedSct struct {
+			Chain struct {
+				Certificates []string `json:"certificates"`
+			} `json:"chain"`
+		} `json:"signedCertificateEmbeddedSct"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, errors.Wrap(err, "failed to decode Fulcio response")
+	}
+
+	certs := parsed.SignedCertificateEmbeddedSct.Chain.Certificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("Fulcio response contained no certificates")
+	}
+
+	return []byte(strings.Join(certs, "\n")), nil
+}
+
+// ParseIdentity reads the signing identity out of a Fulcio-issued
+// certificate chain (PEM, leaf first).
+func ParseIdentity(certChainPEM []byte) (Identity, error) {
+	block, _ := pem.Decode(certChainPEM)
+	if block == nil {
+		return Identity{}, fmt.Errorf("failed to decode PEM block from certificate chain")
+	}
+
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return Identity{}, errors.Wrap(err, "failed to parse leaf certificate")
+	}
+
+	var identity Identity
+	if len(leaf.URIs) > 0 {
+		identity.WorkflowRefURI = leaf.URIs[0].String()
+	}
+	for _, ext := range leaf.Extensions {
+		if ext.Id.Equal(oidIssuer) {
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err == nil {
+				identity.Issuer = issuer
+			} else {
+				// Some issuers emit this extension as a raw UTF-8 string
+				// rather than an ASN.1 UTF8String.
+				identity.Issuer = string(ext.Value)
+			}
+		}
+	}
+
+	return identity, nil
+}
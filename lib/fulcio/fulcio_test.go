@@ -0,0 +1,123 @@
+package fulcio
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func selfSignedCert(t *testing.T, sanURI string, issuer string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	issuerValue, err := asn1.Marshal(issuer)
+	if err != nil {
+		t.Fatalf("asn1.Marshal() error = %v", err)
+	}
+
+	uri, err := url.Parse(sanURI)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(time.Hour),
+		URIs:         []*url.URL{uri},
+		ExtraExtensions: []pkix.Extension{
+			{Id: oidIssuer, Value: issuerValue},
+		},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestParseIdentity(t *testing.T) {
+	certPEM := selfSignedCert(t, "https://github.com/owner/repo/.github/workflows/release.yml@refs/heads/main", "https://token.actions.githubusercontent.com")
+
+	identity, err := ParseIdentity(certPEM)
+	if err != nil {
+		t.Fatalf("ParseIdentity() error = %v", err)
+	}
+
+	if want := "https://github.com/owner/repo/.github/workflows/release.yml@refs/heads/main"; identity.WorkflowRefURI != want {
+		t.Errorf("WorkflowRefURI = %q, want %q", identity.WorkflowRefURI, want)
+	}
+	if want := "https://token.actions.githubusercontent.com"; identity.Issuer != want {
+		t.Errorf("Issuer = %q, want %q", identity.Issuer, want)
+	}
+}
+
+func TestParseIdentityRejectsMalformedPEM(t *testing.T) {
+	if _, err := ParseIdentity([]byte("not a pem")); err == nil {
+		t.Fatal("expected an error for malformed PEM, got nil")
+	}
+}
+
+func TestRequestCertificateSignsSubjectClaim(t *testing.T) {
+	const subject = "repo:owner/repo:ref:refs/heads/main"
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	var captured struct {
+		Credentials struct {
+			OIDCIdentityToken string `json:"oidcIdentityToken"`
+		} `json:"credentials"`
+		PublicKeyRequest struct {
+			ProofOfPossession string `json:"proofOfPossession"`
+		} `json:"publicKeyRequest"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		fmt.Fprint(w, `{"signedCertificateEmbeddedSct":{"chain":{"certificates":["cert"]}}}`)
+	}))
+	defer srv.Close()
+
+	if _, err := RequestCertificate(srv.URL, "the.raw.jwt", subject, key); err != nil {
+		t.Fatalf("RequestCertificate() error = %v", err)
+	}
+
+	if captured.Credentials.OIDCIdentityToken != "the.raw.jwt" {
+		t.Errorf("oidcIdentityToken = %q, want the raw token", captured.Credentials.OIDCIdentityToken)
+	}
+
+	proof, err := base64.StdEncoding.DecodeString(captured.PublicKeyRequest.ProofOfPossession)
+	if err != nil {
+		t.Fatalf("failed to decode proofOfPossession: %v", err)
+	}
+
+	digest := sha256.Sum256([]byte(subject))
+	if !ecdsa.VerifyASN1(&key.PublicKey, digest[:], proof) {
+		t.Error("proofOfPossession does not verify as a signature over the \"sub\" claim")
+	}
+}
@@ -0,0 +1,45 @@
+package github
+
+import "encoding/json"
+
+// Context is the `${{ github }}` context made available to GitHub Actions
+// workflow steps.
+type Context struct {
+	Action          string          `json:"action"`
+	ActionPath      string          `json:"action_path"`
+	Actor           string          `json:"actor"`
+	BaseRef         string          `json:"base_ref"`
+	Event           json.RawMessage `json:"event"`
+	EventName       string          `json:"event_name"`
+	EventPath       string          `json:"event_path"`
+	HeadRef         string          `json:"head_ref"`
+	Job             string          `json:"job"`
+	Ref             string          `json:"ref"`
+	Repository      string          `json:"repository"`
+	RepositoryOwner string          `json:"repository_owner"`
+	RunID           string          `json:"run_id"`
+	RunNumber       string          `json:"run_number"`
+	SHA             string          `json:"sha"`
+	Workflow        string          `json:"workflow"`
+}
+
+// RunnerContext is the `${{ runner }}` context made available to GitHub
+// Actions workflow steps.
+type RunnerContext struct {
+	OS        string `json:"os"`
+	Temp      string `json:"temp"`
+	ToolCache string `json:"tool_cache"`
+}
+
+// AnyContext bundles the raw github and runner contexts so callers can
+// unmarshal both with a single struct.
+type AnyContext struct {
+	Context       Context
+	RunnerContext RunnerContext
+}
+
+// AnyEvent captures the fields we care about from the GitHub event payload,
+// regardless of which event triggered the workflow.
+type AnyEvent struct {
+	Inputs map[string]interface{} `json:"inputs,omitempty"`
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// OIDCTokenClaims are the subset of claims present in a GitHub Actions OIDC
+// token that identify the workflow which produced it. See
+// https://docs.github.com/en/actions/deployment/security-hardening-your-deployments/about-security-hardening-with-openid-connect
+type OIDCTokenClaims struct {
+	Subject        string `json:"sub"`
+	JobWorkflowRef string `json:"job_workflow_ref"`
+	JobWorkflowSha string `json:"job_workflow_sha"`
+	WorkflowRef    string `json:"workflow_ref"`
+}
+
+// RequestOIDCToken requests a GitHub Actions OIDC token for the given
+// audience from the ambient ACTIONS_ID_TOKEN_REQUEST_URL/TOKEN (populated
+// when the job has 'permissions: id-token: write'), returning the raw JWT
+// alongside its unverified claims.
+func RequestOIDCToken(audience string) (rawToken string, claims OIDCTokenClaims, err error) {
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestURL == "" || requestToken == "" {
+		return "", OIDCTokenClaims{}, fmt.Errorf("ACTIONS_ID_TOKEN_REQUEST_URL / ACTIONS_ID_TOKEN_REQUEST_TOKEN are not set; does the job have 'permissions: id-token: write'?")
+	}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", OIDCTokenClaims{}, errors.Wrap(err, "failed to parse ACTIONS_ID_TOKEN_REQUEST_URL")
+	}
+	q := u.Query()
+	q.Set("audience", audience)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", OIDCTokenClaims{}, errors.Wrap(err, "failed to build OIDC token request")
+	}
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", OIDCTokenClaims{}, errors.Wrap(err, "failed to request OIDC token")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", OIDCTokenClaims{}, fmt.Errorf("OIDC token request failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", OIDCTokenClaims{}, errors.Wrap(err, "failed to decode OIDC token response")
+	}
+
+	claims, err = parseOIDCClaims(body.Value)
+	if err != nil {
+		return "", OIDCTokenClaims{}, err
+	}
+
+	return body.Value, claims, nil
+}
+
+// parseOIDCClaims decodes, without verifying, the claims in the payload
+// segment of a JWT.
+func parseOIDCClaims(rawToken string) (OIDCTokenClaims, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return OIDCTokenClaims{}, fmt.Errorf("malformed OIDC token: expected 3 dot-separated segments, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return OIDCTokenClaims{}, errors.Wrap(err, "failed to base64-decode OIDC token payload")
+	}
+
+	var claims OIDCTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return OIDCTokenClaims{}, errors.Wrap(err, "failed to unmarshal OIDC token claims")
+	}
+	return claims, nil
+}
+
+// SplitJobWorkflowRef splits a job_workflow_ref claim of the form
+// "owner/repo/path/to/workflow.yml@ref" into the repository ("owner/repo"),
+// the workflow file path, and the ref.
+func SplitJobWorkflowRef(jobWorkflowRef string) (repo, path, ref string, err error) {
+	atIdx := strings.LastIndex(jobWorkflowRef, "@")
+	if atIdx < 0 {
+		return "", "", "", fmt.Errorf("malformed job_workflow_ref: missing '@ref': %q", jobWorkflowRef)
+	}
+	withoutRef := jobWorkflowRef[:atIdx]
+	ref = jobWorkflowRef[atIdx+1:]
+
+	parts := strings.SplitN(withoutRef, "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("malformed job_workflow_ref: expected owner/repo/path, got %q", withoutRef)
+	}
+
+	return parts[0] + "/" + parts[1], parts[2], ref, nil
+}
@@ -0,0 +1,25 @@
+package github
+
+import "testing"
+
+func TestSplitJobWorkflowRef(t *testing.T) {
+	repo, path, ref, err := SplitJobWorkflowRef("owner/repo/.github/workflows/release.yml@refs/heads/main")
+	if err != nil {
+		t.Fatalf("SplitJobWorkflowRef() error = %v", err)
+	}
+	if repo != "owner/repo" {
+		t.Errorf("repo = %q, want %q", repo, "owner/repo")
+	}
+	if path != ".github/workflows/release.yml" {
+		t.Errorf("path = %q, want %q", path, ".github/workflows/release.yml")
+	}
+	if ref != "refs/heads/main" {
+		t.Errorf("ref = %q, want %q", ref, "refs/heads/main")
+	}
+}
+
+func TestSplitJobWorkflowRefRejectsMissingRef(t *testing.T) {
+	if _, _, _, err := SplitJobWorkflowRef("owner/repo/.github/workflows/release.yml"); err == nil {
+		t.Fatal("expected an error for a job_workflow_ref with no '@ref', got nil")
+	}
+}
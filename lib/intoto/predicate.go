@@ -0,0 +1,86 @@
+package intoto
+
+import "encoding/json"
+
+// Predicate type URIs for the built-in predicate builders that are not SLSA
+// provenance.
+const (
+	PredicateSPDX      = "https://spdx.dev/Document"
+	PredicateCycloneDX = "https://cyclonedx.org/bom"
+	PredicateLink      = "https://in-toto.io/Link/v1"
+)
+
+// Names used to select a built-in PredicateBuilder via --predicate-type.
+const (
+	PredicateTypeSLSAProvenance = "slsaprovenance"
+	PredicateTypeSPDX           = "spdx"
+	PredicateTypeCycloneDX      = "cyclonedx"
+	PredicateTypeLink           = "link"
+)
+
+// PredicateBuilder builds the predicate portion of an in-toto Statement.
+type PredicateBuilder interface {
+	// Type returns the in-toto predicateType URI this builder produces.
+	Type() string
+	// Build returns the predicate value to embed in the Statement.
+	Build() (interface{}, error)
+}
+
+// GenericStatement is an in-toto Statement whose predicate shape is
+// determined by a PredicateBuilder, rather than being hard-coded to SLSA
+// provenance.
+type GenericStatement struct {
+	StatementHeader
+	Predicate interface{} `json:"predicate"`
+}
+
+// NewGenericStatement builds a Statement over subject, using builder to
+// produce the predicate.
+func NewGenericStatement(subject []Subject, builder PredicateBuilder) (GenericStatement, error) {
+	predicate, err := builder.Build()
+	if err != nil {
+		return GenericStatement{}, err
+	}
+
+	return GenericStatement{
+		StatementHeader: StatementHeader{
+			Type:          StatementInTotoV01,
+			PredicateType: builder.Type(),
+			Subject:       subject,
+		},
+		Predicate: predicate,
+	}, nil
+}
+
+// RawPredicateBuilder builds a predicate straight from a pre-formed JSON
+// document, for predicate types (SPDX, CycloneDX, link, ...) whose shape
+// this package does not otherwise model.
+type RawPredicateBuilder struct {
+	PredicateType string
+	Raw           json.RawMessage
+}
+
+// Type returns the configured predicateType.
+func (b RawPredicateBuilder) Type() string {
+	return b.PredicateType
+}
+
+// Build returns the raw JSON document as-is.
+func (b RawPredicateBuilder) Build() (interface{}, error) {
+	return b.Raw, nil
+}
+
+// NewSPDXBuilder wraps raw in the SPDX document predicateType.
+func NewSPDXBuilder(raw json.RawMessage) PredicateBuilder {
+	return RawPredicateBuilder{PredicateType: PredicateSPDX, Raw: raw}
+}
+
+// NewCycloneDXBuilder wraps raw in the CycloneDX BOM predicateType.
+func NewCycloneDXBuilder(raw json.RawMessage) PredicateBuilder {
+	return RawPredicateBuilder{PredicateType: PredicateCycloneDX, Raw: raw}
+}
+
+// NewLinkBuilder wraps raw in the in-toto Link predicateType.
+func NewLinkBuilder(raw json.RawMessage) PredicateBuilder {
+	return RawPredicateBuilder{PredicateType: PredicateLink, Raw: raw}
+}
@@ -0,0 +1,31 @@
+package intoto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewGenericStatement(t *testing.T) {
+	subject := []Subject{{Name: "sbom.json", Digest: DigestSet{"sha256": "deadbeef"}}}
+	builder := NewSPDXBuilder(json.RawMessage(`{"spdxVersion":"SPDX-2.3"}`))
+
+	stmt, err := NewGenericStatement(subject, builder)
+	if err != nil {
+		t.Fatalf("NewGenericStatement() error = %v", err)
+	}
+	if stmt.PredicateType != PredicateSPDX {
+		t.Errorf("PredicateType = %q, want %q", stmt.PredicateType, PredicateSPDX)
+	}
+	if len(stmt.Subject) != 1 || stmt.Subject[0].Name != "sbom.json" {
+		t.Errorf("Subject = %+v, want subject to contain sbom.json", stmt.Subject)
+	}
+
+	out, err := json.Marshal(stmt)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got := string(out); !strings.Contains(got, `"spdxVersion":"SPDX-2.3"`) {
+		t.Errorf("marshaled statement = %s, want it to embed the raw predicate", got)
+	}
+}
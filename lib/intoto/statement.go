@@ -0,0 +1,137 @@
+// Package intoto implements the subset of the in-toto attestation spec
+// (https://github.com/in-toto/attestation) used to describe SLSA
+// provenance for GitHub Actions builds.
+package intoto
+
+const (
+	// StatementInTotoV01 is the in-toto Statement type.
+	StatementInTotoV01 = "https://in-toto.io/Statement/v0.1"
+	// PredicateSLSAProvenanceV01 is the predicateType for SLSA v0.2 provenance.
+	PredicateSLSAProvenanceV01 = "https://slsa.dev/provenance/v0.2"
+)
+
+// Subject identifies one of the artifacts the Statement makes claims about.
+type Subject struct {
+	Name   string    `json:"name"`
+	Digest DigestSet `json:"digest"`
+}
+
+// DigestSet is a set of digests, keyed by algorithm name (e.g. "sha256").
+type DigestSet map[string]string
+
+// StatementHeader is the set of fields common to every in-toto Statement,
+// regardless of predicate type.
+type StatementHeader struct {
+	Type          string    `json:"_type"`
+	PredicateType string    `json:"predicateType"`
+	Subject       []Subject `json:"subject"`
+}
+
+// Statement is an in-toto Statement carrying a SLSA v0.2 provenance
+// predicate.
+type Statement struct {
+	StatementHeader
+	Predicate ProvenancePredicate `json:"predicate"`
+}
+
+// ProvenancePredicate is the SLSA v0.2 provenance predicate.
+type ProvenancePredicate struct {
+	Builder   Builder  `json:"builder"`
+	Recipe    Recipe   `json:"recipe"`
+	Metadata  Metadata `json:"metadata"`
+	Materials []Item   `json:"materials"`
+	// Invocation is a pointer so that the zero value (the common case,
+	// when the builder ID isn't derived from an OIDC token) is omitted
+	// from the marshaled JSON: "omitempty" has no effect on a non-pointer
+	// struct field, since a zero-value struct is never "empty".
+	Invocation *Invocation `json:"invocation,omitempty"`
+}
+
+// Invocation describes how the build was invoked.
+type Invocation struct {
+	ConfigSource ConfigSource `json:"configSource,omitempty"`
+}
+
+// ConfigSource identifies the source that defined the build's external
+// parameters, e.g. the workflow file that was invoked.
+type ConfigSource struct {
+	URI        string    `json:"uri"`
+	Digest     DigestSet `json:"digest,omitempty"`
+	EntryPoint string    `json:"entryPoint,omitempty"`
+}
+
+// Builder identifies the entity that executed the build steps.
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// Recipe describes the steps that were performed to produce the artifacts.
+type Recipe struct {
+	Type              string      `json:"type"`
+	DefinedInMaterial int         `json:"definedInMaterial"`
+	EntryPoint        string      `json:"entryPoint,omitempty"`
+	Arguments         interface{} `json:"arguments,omitempty"`
+}
+
+// Item is a material or product referenced by a Recipe, identified by URI
+// and, optionally, digest.
+type Item struct {
+	URI    string    `json:"uri"`
+	Digest DigestSet `json:"digest,omitempty"`
+}
+
+// Metadata holds additional information about the build.
+type Metadata struct {
+	BuildInvocationID string `json:"buildInvocationId,omitempty"`
+}
+
+// Option configures a Statement produced by SLSAProvenanceStatement.
+type Option func(*Statement)
+
+// WithSubject sets the Statement's subject list.
+func WithSubject(subject []Subject) Option {
+	return func(stmt *Statement) {
+		stmt.Subject = subject
+	}
+}
+
+// WithBuilder sets the predicate's builder ID.
+func WithBuilder(id string) Option {
+	return func(stmt *Statement) {
+		stmt.Predicate.Builder.ID = id
+	}
+}
+
+// WithMetadata sets the predicate's build invocation ID.
+func WithMetadata(buildInvocationID string) Option {
+	return func(stmt *Statement) {
+		stmt.Predicate.Metadata.BuildInvocationID = buildInvocationID
+	}
+}
+
+// WithInvocation sets the predicate's invocation, e.g. the config source
+// that defined the build. A zero-value Invocation (no config source) is a
+// no-op, leaving the predicate's invocation field unset.
+func WithInvocation(invocation Invocation) Option {
+	return func(stmt *Statement) {
+		if invocation.ConfigSource.URI == "" {
+			return
+		}
+		stmt.Predicate.Invocation = &invocation
+	}
+}
+
+// SLSAProvenanceStatement creates a new Statement carrying a SLSA v0.2
+// provenance predicate, applying the given Options.
+func SLSAProvenanceStatement(opts ...Option) Statement {
+	stmt := Statement{
+		StatementHeader: StatementHeader{
+			Type:          StatementInTotoV01,
+			PredicateType: PredicateSLSAProvenanceV01,
+		},
+	}
+	for _, opt := range opts {
+		opt(&stmt)
+	}
+	return stmt
+}
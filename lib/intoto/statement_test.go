@@ -0,0 +1,54 @@
+package intoto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSLSAProvenanceStatementOmitsInvocationByDefault(t *testing.T) {
+	stmt := SLSAProvenanceStatement(
+		WithSubject([]Subject{{Name: "artifact.tar.gz", Digest: DigestSet{"sha256": "deadbeef"}}}),
+		WithBuilder("https://github.com/owner/repo/Attestations/GitHubHostedActions@v1"),
+		WithMetadata("https://github.com/owner/repo/actions/runs/1"),
+		WithInvocation(Invocation{}),
+	)
+
+	if stmt.Predicate.Invocation != nil {
+		t.Errorf("Predicate.Invocation = %+v, want nil", stmt.Predicate.Invocation)
+	}
+
+	raw, err := json.Marshal(stmt)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(raw), `"invocation"`) {
+		t.Errorf("marshaled statement = %s, want no \"invocation\" key", raw)
+	}
+}
+
+func TestSLSAProvenanceStatementWithInvocation(t *testing.T) {
+	stmt := SLSAProvenanceStatement(
+		WithSubject([]Subject{{Name: "artifact.tar.gz", Digest: DigestSet{"sha256": "deadbeef"}}}),
+		WithInvocation(Invocation{ConfigSource: ConfigSource{
+			URI:        "git+https://github.com/owner/repo@refs/heads/main",
+			Digest:     DigestSet{"sha1": "abc123"},
+			EntryPoint: ".github/workflows/release.yml",
+		}}),
+	)
+
+	if stmt.Predicate.Invocation == nil {
+		t.Fatal("Predicate.Invocation = nil, want non-nil")
+	}
+	if stmt.Predicate.Invocation.ConfigSource.URI != "git+https://github.com/owner/repo@refs/heads/main" {
+		t.Errorf("ConfigSource.URI = %q, want %q", stmt.Predicate.Invocation.ConfigSource.URI, "git+https://github.com/owner/repo@refs/heads/main")
+	}
+
+	raw, err := json.Marshal(stmt)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(raw), `"invocation"`) {
+		t.Errorf("marshaled statement = %s, want it to contain \"invocation\"", raw)
+	}
+}
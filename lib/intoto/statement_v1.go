@@ -0,0 +1,87 @@
+package intoto
+
+const (
+	// PredicateSLSAProvenanceV1 is the predicateType for SLSA v1.0 provenance.
+	PredicateSLSAProvenanceV1 = "https://slsa.dev/provenance/v1"
+	// BuildTypeGitHubActionsWorkflow identifies a build carried out by a
+	// GitHub Actions workflow run.
+	BuildTypeGitHubActionsWorkflow = "https://github.com/Attestations/GitHubActionsWorkflow@v1"
+)
+
+// StatementV1 is an in-toto Statement carrying a SLSA v1.0 provenance
+// predicate.
+type StatementV1 struct {
+	StatementHeader
+	Predicate ProvenancePredicateV1 `json:"predicate"`
+}
+
+// ProvenancePredicateV1 is the SLSA v1.0 provenance predicate.
+type ProvenancePredicateV1 struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs to the build.
+type BuildDefinition struct {
+	BuildType            string                 `json:"buildType"`
+	ExternalParameters   ExternalParameters     `json:"externalParameters"`
+	InternalParameters   map[string]interface{} `json:"internalParameters,omitempty"`
+	ResolvedDependencies []Item                 `json:"resolvedDependencies,omitempty"`
+}
+
+// ExternalParameters are the parameters that a build platform user can
+// control, e.g. which workflow ran and what it was invoked with.
+type ExternalParameters struct {
+	Workflow string      `json:"workflow"`
+	Inputs   interface{} `json:"inputs,omitempty"`
+}
+
+// RunDetails describes the build platform's execution of the build.
+type RunDetails struct {
+	Builder  Builder     `json:"builder"`
+	Metadata RunMetadata `json:"metadata"`
+}
+
+// RunMetadata holds additional information about a specific build run.
+type RunMetadata struct {
+	InvocationID string `json:"invocationId,omitempty"`
+}
+
+// OptionV1 configures a StatementV1 produced by SLSAProvenanceV1Statement.
+type OptionV1 func(*StatementV1)
+
+// WithSubjectV1 sets the Statement's subject list.
+func WithSubjectV1(subject []Subject) OptionV1 {
+	return func(stmt *StatementV1) {
+		stmt.Subject = subject
+	}
+}
+
+// WithBuildDefinition sets the predicate's buildDefinition.
+func WithBuildDefinition(bd BuildDefinition) OptionV1 {
+	return func(stmt *StatementV1) {
+		stmt.Predicate.BuildDefinition = bd
+	}
+}
+
+// WithRunDetails sets the predicate's runDetails.
+func WithRunDetails(rd RunDetails) OptionV1 {
+	return func(stmt *StatementV1) {
+		stmt.Predicate.RunDetails = rd
+	}
+}
+
+// SLSAProvenanceV1Statement creates a new Statement carrying a SLSA v1.0
+// provenance predicate, applying the given Options.
+func SLSAProvenanceV1Statement(opts ...OptionV1) StatementV1 {
+	stmt := StatementV1{
+		StatementHeader: StatementHeader{
+			Type:          StatementInTotoV01,
+			PredicateType: PredicateSLSAProvenanceV1,
+		},
+	}
+	for _, opt := range opts {
+		opt(&stmt)
+	}
+	return stmt
+}
@@ -0,0 +1,43 @@
+package intoto
+
+import "testing"
+
+func TestSLSAProvenanceV1Statement(t *testing.T) {
+	subject := []Subject{{Name: "artifact.tar.gz", Digest: DigestSet{"sha256": "deadbeef"}}}
+
+	stmt := SLSAProvenanceV1Statement(
+		WithSubjectV1(subject),
+		WithBuildDefinition(BuildDefinition{
+			BuildType: BuildTypeGitHubActionsWorkflow,
+			ExternalParameters: ExternalParameters{
+				Workflow: "release.yml",
+			},
+			ResolvedDependencies: []Item{
+				{URI: "git+https://github.com/owner/repo", Digest: DigestSet{"sha1": "abc123"}},
+			},
+		}),
+		WithRunDetails(RunDetails{
+			Builder:  Builder{ID: "https://github.com/owner/repo/Attestations/GitHubHostedActions@v1"},
+			Metadata: RunMetadata{InvocationID: "https://github.com/owner/repo/actions/runs/1"},
+		}),
+	)
+
+	if stmt.Type != StatementInTotoV01 {
+		t.Errorf("Type = %q, want %q", stmt.Type, StatementInTotoV01)
+	}
+	if stmt.PredicateType != PredicateSLSAProvenanceV1 {
+		t.Errorf("PredicateType = %q, want %q", stmt.PredicateType, PredicateSLSAProvenanceV1)
+	}
+	if len(stmt.Subject) != 1 || stmt.Subject[0].Name != "artifact.tar.gz" {
+		t.Errorf("Subject = %+v, want subject to contain artifact.tar.gz", stmt.Subject)
+	}
+	if stmt.Predicate.BuildDefinition.ExternalParameters.Workflow != "release.yml" {
+		t.Errorf("ExternalParameters.Workflow = %q, want %q", stmt.Predicate.BuildDefinition.ExternalParameters.Workflow, "release.yml")
+	}
+	if len(stmt.Predicate.BuildDefinition.ResolvedDependencies) != 1 {
+		t.Errorf("ResolvedDependencies = %+v, want 1 entry", stmt.Predicate.BuildDefinition.ResolvedDependencies)
+	}
+	if stmt.Predicate.RunDetails.Metadata.InvocationID != "https://github.com/owner/repo/actions/runs/1" {
+		t.Errorf("InvocationID = %q, want run URL", stmt.Predicate.RunDetails.Metadata.InvocationID)
+	}
+}
@@ -0,0 +1,229 @@
+// Package rekor implements the minimal subset of the Sigstore Rekor
+// transparency-log protocol needed to upload a hashedrekord entry for a
+// signed DSSE envelope and to later verify its inclusion proof offline.
+package rekor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultURL is the public Sigstore Rekor instance.
+const DefaultURL = "https://rekor.sigstore.dev"
+
+// InclusionProof is the Merkle audit path proving that an Entry's body is
+// included in the log's tree at the claimed root hash.
+type InclusionProof struct {
+	LogIndex int64    `json:"logIndex"`
+	RootHash string   `json:"rootHash"`
+	TreeSize int64    `json:"treeSize"`
+	Hashes   []string `json:"hashes"`
+}
+
+// Entry is an uploaded Rekor hashedrekord entry, with enough of the API
+// response captured to verify its inclusion proof offline later.
+type Entry struct {
+	UUID           string         `json:"uuid"`
+	LogIndex       int64          `json:"logIndex"`
+	IntegratedTime int64          `json:"integratedTime"`
+	Body           []byte         `json:"body"`
+	InclusionProof InclusionProof `json:"inclusionProof"`
+}
+
+// UploadHashedRekord uploads a hashedrekord entry for (certPEM, sig,
+// payload) to the Rekor instance at rekorURL, returning the resulting log
+// entry.
+func UploadHashedRekord(rekorURL string, certPEM, sig, payload []byte) (*Entry, error) {
+	digest := sha256.Sum256(payload)
+
+	body, err := json.Marshal(struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Spec       struct {
+			Signature struct {
+				Content   string `json:"content"`
+				PublicKey struct {
+					Content string `json:"content"`
+				} `json:"publicKey"`
+			} `json:"signature"`
+			Data struct {
+				Hash struct {
+					Algorithm string `json:"algorithm"`
+					Value     string `json:"value"`
+				} `json:"hash"`
+			} `json:"data"`
+		} `json:"spec"`
+	}{
+		APIVersion: "0.0.1",
+		Kind:       "hashedrekord",
+		Spec: struct {
+			Signature struct {
+				Content   string `json:"content"`
+				PublicKey struct {
+					Content string `json:"content"`
+				} `json:"publicKey"`
+			} `json:"signature"`
+			Data struct {
+				Hash struct {
+					Algorithm string `json:"algorithm"`
+					Value     string `json:"value"`
+				} `json:"hash"`
+			} `json:"data"`
+		}{
+			Signature: struct {
+				Content   string `json:"content"`
+				PublicKey struct {
+					Content string `json:"content"`
+				} `json:"publicKey"`
+			}{
+				Content: base64.StdEncoding.EncodeToString(sig),
+				PublicKey: struct {
+					Content string `json:"content"`
+				}{Content: base64.StdEncoding.EncodeToString(certPEM)},
+			},
+			Data: struct {
+				Hash struct {
+					Algorithm string `json:"algorithm"`
+					Value     string `json:"value"`
+				} `json:"hash"`
+			}{Hash: struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			}{Algorithm: "sha256", Value: hex.EncodeToString(digest[:])}},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal hashedrekord entry")
+	}
+
+	resp, err := http.Post(strings.TrimSuffix(rekorURL, "/")+"/api/v1/log/entries", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to upload entry to Rekor")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("Rekor upload failed: %s", resp.Status)
+	}
+
+	var raw map[string]struct {
+		LogIndex       int64  `json:"logIndex"`
+		IntegratedTime int64  `json:"integratedTime"`
+		Body           string `json:"body"`
+		Verification   struct {
+			InclusionProof InclusionProof `json:"inclusionProof"`
+		} `json:"verification"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, errors.Wrap(err, "failed to decode Rekor response")
+	}
+	if len(raw) != 1 {
+		return nil, fmt.Errorf("expected exactly one Rekor entry in response, got %d", len(raw))
+	}
+
+	for uuid, e := range raw {
+		entryBody, err := base64.StdEncoding.DecodeString(e.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode entry body")
+		}
+		return &Entry{
+			UUID:           uuid,
+			LogIndex:       e.LogIndex,
+			IntegratedTime: e.IntegratedTime,
+			Body:           entryBody,
+			InclusionProof: e.Verification.InclusionProof,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("internal error: no Rekor entry found")
+}
+
+// leafHash is the RFC 6962 hash of a Merkle tree leaf.
+func leafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// hashChildren is the RFC 6962 hash of a Merkle tree interior node.
+func hashChildren(l, r []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(l)
+	h.Write(r)
+	return h.Sum(nil)
+}
+
+// rootFromInclusionProof recomputes the Merkle tree root hash implied by an
+// audit path of sibling hashes for the leaf at index leafIndex in a tree
+// with treeSize leaves, following the RFC 6962 inclusion-proof algorithm.
+func rootFromInclusionProof(leafIndex, treeSize int64, hash []byte, proof [][]byte) ([]byte, error) {
+	if leafIndex < 0 || leafIndex >= treeSize {
+		return nil, fmt.Errorf("leaf index %d out of range for tree size %d", leafIndex, treeSize)
+	}
+
+	node, lastNode := leafIndex, treeSize-1
+	for _, sibling := range proof {
+		if lastNode == 0 {
+			return nil, fmt.Errorf("inclusion proof is longer than expected")
+		}
+		if node%2 == 1 || node == lastNode {
+			hash = hashChildren(sibling, hash)
+			for node%2 == 0 && node != 0 {
+				node /= 2
+				lastNode /= 2
+			}
+		} else {
+			hash = hashChildren(hash, sibling)
+		}
+		node /= 2
+		lastNode /= 2
+	}
+	if lastNode != 0 {
+		return nil, fmt.Errorf("inclusion proof is shorter than expected")
+	}
+	return hash, nil
+}
+
+// VerifyInclusionProof checks, offline, that entry's body is consistent
+// with the Merkle audit path in entry.InclusionProof, i.e. that hashing the
+// body up the claimed path reproduces the claimed root hash.
+//
+// This does not authenticate the root hash itself against Rekor's signed
+// tree head; callers that need that guarantee must independently confirm
+// the root against a trusted checkpoint.
+func VerifyInclusionProof(entry *Entry) error {
+	proof := make([][]byte, len(entry.InclusionProof.Hashes))
+	for i, h := range entry.InclusionProof.Hashes {
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode inclusion proof hash %d", i)
+		}
+		proof[i] = b
+	}
+
+	wantRoot, err := hex.DecodeString(entry.InclusionProof.RootHash)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode inclusion proof root hash")
+	}
+
+	gotRoot, err := rootFromInclusionProof(entry.InclusionProof.LogIndex, entry.InclusionProof.TreeSize, leafHash(entry.Body), proof)
+	if err != nil {
+		return errors.Wrap(err, "failed to recompute Merkle root")
+	}
+
+	if !bytes.Equal(gotRoot, wantRoot) {
+		return fmt.Errorf("inclusion proof root hash mismatch: computed %x, want %x", gotRoot, wantRoot)
+	}
+
+	return nil
+}